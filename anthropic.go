@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	anthropicURL           = "https://api.anthropic.com/v1/messages"
+	anthropicVersion       = "2023-06-01"
+	anthropicDefaultModel  = "claude-3-5-haiku-latest"
+	anthropicDefaultTokens = 1024
+)
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// anthropicProvider talks to the Anthropic messages API.
+type anthropicProvider struct {
+	apiKey string
+	model  string
+}
+
+func newAnthropicProvider() (Provider, error) {
+	apiKey, err := requireEnv("ANTHROPIC_API_KEY", "Get your API key from: https://console.anthropic.com/\n   Then run: export ANTHROPIC_API_KEY=your_api_key_here")
+	if err != nil {
+		return nil, err
+	}
+
+	model := os.Getenv("GENIE_ANTHROPIC_MODEL")
+	if model == "" {
+		model = anthropicDefaultModel
+	}
+
+	return &anthropicProvider{apiKey: apiKey, model: model}, nil
+}
+
+func (p *anthropicProvider) Name() string { return "anthropic" }
+
+func (p *anthropicProvider) Generate(ctx context.Context, prompt string, opts GenerateOptions) (Completion, error) {
+	model := p.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+
+	jsonBody, err := json.Marshal(anthropicRequest{
+		Model:     model,
+		MaxTokens: anthropicDefaultTokens,
+		Messages:  []anthropicMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	text, err := withRetry(ctx, func() (string, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", anthropicURL, bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-api-key", p.apiKey)
+		req.Header.Set("anthropic-version", anthropicVersion)
+
+		client := &http.Client{Timeout: generateTimeout(opts)}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+
+		var anthResp anthropicResponse
+		if err := json.Unmarshal(body, &anthResp); err != nil {
+			return "", err
+		}
+
+		if anthResp.Error != nil {
+			return "", fmt.Errorf("anthropic API error: %s", anthResp.Error.Message)
+		}
+		if len(anthResp.Content) == 0 {
+			return "", fmt.Errorf("no response from Anthropic API")
+		}
+
+		return strings.TrimSpace(anthResp.Content[0].Text), nil
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	return Completion{Text: text}, nil
+}
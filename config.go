@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// configFileName is the repo-root config file validation rules are loaded
+// from, if present.
+const configFileName = ".genie.yaml"
+
+// LoadConfig reads validation rules from path, falling back to
+// DefaultRules for anything not set and when the file doesn't exist at all.
+// genie has no YAML dependency, so only the small subset it actually needs
+// is supported: scalar "key: value" lines plus a single "types:" list.
+//
+// Example .genie.yaml:
+//
+//	types:
+//	  - feat
+//	  - fix
+//	  - docs
+//	max_subject_length: 72
+//	scope_regex: "^[a-z0-9-]+$"
+//	emoji_required: true
+//	emoji_leading: true
+func LoadConfig(path string) (ValidationRules, error) {
+	rules := DefaultRules()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rules, nil
+		}
+		return rules, err
+	}
+
+	var inTypesList bool
+
+	for _, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimRight(raw, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "-") {
+			if inTypesList {
+				rules.AllowedTypes = append(rules.AllowedTypes, unquote(strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))))
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = unquote(strings.TrimSpace(value))
+
+		inTypesList = key == "types" && value == ""
+		if inTypesList {
+			rules.AllowedTypes = nil
+			continue
+		}
+
+		switch key {
+		case "max_subject_length":
+			if n, err := strconv.Atoi(value); err == nil {
+				rules.MaxSubjectLen = n
+			}
+		case "scope_regex":
+			rules.ScopeRegex = value
+		case "emoji_required":
+			rules.EmojiRequired = value == "true"
+		case "emoji_leading":
+			rules.EmojiLeading = value == "true"
+		}
+	}
+
+	return rules, nil
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, `"'`)
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParsedCommit is a commit message broken into its Conventional Commits
+// parts: "emoji type(scope)!: subject", an optional wrapped body, and
+// optional trailing footers (BREAKING CHANGE:, Refs:, Co-authored-by:, ...).
+type ParsedCommit struct {
+	Emoji    string
+	Type     string
+	Scope    string
+	Breaking bool
+	Subject  string
+	Body     string
+	Footers  []Footer
+}
+
+// Footer is a single "Key: value" trailer line.
+type Footer struct {
+	Key   string
+	Value string
+}
+
+// headerPattern matches "emoji type(scope)!: subject". The emoji is
+// optional and matched loosely (any symbol-category rune) since genie's
+// emoji guide covers far more entries than any one regex should hardcode.
+var headerPattern = regexp.MustCompile(`^(?:([\p{So}\x{FE0F}]+)\s+)?([a-zA-Z]+)(?:\(([^)]+)\))?(!)?:\s*(.+)$`)
+
+// footerLinePattern matches a single git trailer line, e.g. "Refs: #123" or
+// "BREAKING CHANGE: removes the v1 endpoint".
+var footerLinePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9 -]*|BREAKING CHANGE):\s?(.*)$`)
+
+// Parse breaks a full commit message (header, optional body, optional
+// footers) into a ParsedCommit. It returns an error if the header doesn't
+// match the "type(scope)!: subject" shape at all.
+func Parse(message string) (*ParsedCommit, error) {
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	lines := strings.Split(message, "\n")
+
+	header := strings.TrimSpace(lines[0])
+	m := headerPattern.FindStringSubmatch(header)
+	if m == nil {
+		return nil, fmt.Errorf("header %q does not match \"type(scope)!: subject\"", header)
+	}
+
+	pc := &ParsedCommit{
+		Emoji:    m[1],
+		Type:     m[2],
+		Scope:    m[3],
+		Breaking: m[4] == "!",
+		Subject:  strings.TrimSpace(m[5]),
+	}
+
+	rest := strings.TrimSpace(strings.Join(lines[1:], "\n"))
+	if rest == "" {
+		return pc, nil
+	}
+
+	pc.Body, pc.Footers = splitFooters(rest)
+	return pc, nil
+}
+
+// splitFooters looks at the last blank-line-delimited block of the message
+// and, if every line in it looks like a trailer, peels it off as footers.
+func splitFooters(rest string) (string, []Footer) {
+	blocks := strings.Split(rest, "\n\n")
+	lastBlock := strings.Split(strings.TrimSpace(blocks[len(blocks)-1]), "\n")
+
+	var footers []Footer
+	for _, line := range lastBlock {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		m := footerLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			return rest, nil
+		}
+		footers = append(footers, Footer{Key: m[1], Value: m[2]})
+	}
+
+	if len(footers) == 0 {
+		return rest, nil
+	}
+
+	body := strings.TrimSpace(strings.Join(blocks[:len(blocks)-1], "\n\n"))
+	return body, footers
+}
+
+// Header reconstructs the "emoji type(scope)!: subject" line from pc.
+func (pc *ParsedCommit) Header() string {
+	var b strings.Builder
+	if pc.Emoji != "" {
+		b.WriteString(pc.Emoji)
+		b.WriteString(" ")
+	}
+	b.WriteString(pc.Type)
+	if pc.Scope != "" {
+		fmt.Fprintf(&b, "(%s)", pc.Scope)
+	}
+	if pc.Breaking {
+		b.WriteString("!")
+	}
+	b.WriteString(": ")
+	b.WriteString(pc.Subject)
+	return b.String()
+}
+
+// String reassembles pc into a full commit message, guaranteeing the blank
+// line between subject/body/footers that git interpret-trailers expects.
+func (pc *ParsedCommit) String() string {
+	var b strings.Builder
+	b.WriteString(pc.Header())
+
+	if pc.Body != "" {
+		b.WriteString("\n\n")
+		b.WriteString(pc.Body)
+	}
+
+	if len(pc.Footers) > 0 {
+		b.WriteString("\n\n")
+		for i, f := range pc.Footers {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s: %s", f.Key, f.Value)
+		}
+	}
+
+	return b.String()
+}
+
+// ValidationRules configures what a compliant commit message looks like.
+// The zero-value fields are filled in by DefaultRules; a .genie.yaml at the
+// repo root can override any of them via LoadConfig.
+type ValidationRules struct {
+	AllowedTypes  []string
+	MaxSubjectLen int
+	ScopeRegex    string
+	EmojiRequired bool
+	// EmojiLeading requires the emoji to be the very first thing in the
+	// header (what headerPattern parses into pc.Emoji). When false, an
+	// emoji anywhere in the subject also satisfies EmojiRequired.
+	EmojiLeading bool
+}
+
+// DefaultRules mirrors the conventions genie's own prompt already asks
+// Gemini to follow, so validation catches the cases where the model didn't.
+func DefaultRules() ValidationRules {
+	return ValidationRules{
+		AllowedTypes:  []string{"feat", "fix", "docs", "style", "refactor", "test", "chore", "perf", "ci", "build", "revert"},
+		MaxSubjectLen: 72,
+		ScopeRegex:    `^[a-z0-9-]+$`,
+		EmojiRequired: true,
+		EmojiLeading:  true,
+	}
+}
+
+// emojiGuide pairs each conventional type with the emoji genie's prompt
+// asks for, used to check and repair mismatched pairings.
+var emojiGuide = map[string]string{
+	"feat":     "✨",
+	"fix":      "🐛",
+	"docs":     "📝",
+	"style":    "💄",
+	"refactor": "♻️",
+	"test":     "✅",
+	"chore":    "🔧",
+	"perf":     "⚡",
+	"ci":       "👷",
+	"build":    "📦",
+	"revert":   "⏪",
+}
+
+// emojiPattern matches a single emoji-ish symbol anywhere in a string. When
+// rules.EmojiLeading is false, an emoji found here satisfies EmojiRequired
+// even if it isn't the leading character headerPattern captures into
+// pc.Emoji.
+var emojiPattern = regexp.MustCompile(`[\p{So}\x{FE0F}]`)
+
+// ValidationError describes one way a parsed commit violates the rules.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Validate checks pc against rules and returns every violation found; a nil
+// slice means pc is fully compliant.
+func Validate(pc *ParsedCommit, rules ValidationRules) []ValidationError {
+	var errs []ValidationError
+
+	if !containsString(rules.AllowedTypes, pc.Type) {
+		errs = append(errs, ValidationError{"type", fmt.Sprintf("%q is not one of %v", pc.Type, rules.AllowedTypes)})
+	}
+
+	if rules.EmojiRequired && pc.Emoji == "" {
+		if rules.EmojiLeading || !emojiPattern.MatchString(pc.Subject) {
+			errs = append(errs, ValidationError{"emoji", "missing leading emoji"})
+		}
+	}
+
+	if want, ok := emojiGuide[pc.Type]; ok && pc.Emoji != "" && pc.Emoji != want {
+		errs = append(errs, ValidationError{"emoji", fmt.Sprintf("type %q is usually paired with %s, got %s", pc.Type, want, pc.Emoji)})
+	}
+
+	if rules.ScopeRegex != "" && pc.Scope != "" {
+		if re, err := regexp.Compile(rules.ScopeRegex); err == nil && !re.MatchString(pc.Scope) {
+			errs = append(errs, ValidationError{"scope", fmt.Sprintf("%q does not match %s", pc.Scope, rules.ScopeRegex)})
+		}
+	}
+
+	if rules.MaxSubjectLen > 0 && utf8.RuneCountInString(pc.Header()) > rules.MaxSubjectLen {
+		errs = append(errs, ValidationError{"subject", fmt.Sprintf("header is %d chars, max is %d", utf8.RuneCountInString(pc.Header()), rules.MaxSubjectLen)})
+	}
+
+	if pc.Subject == "" {
+		errs = append(errs, ValidationError{"subject", "subject is empty"})
+	} else if verb, _, ok := splitFirstWord(pc.Subject); ok && isPastTense(verb) {
+		errs = append(errs, ValidationError{"subject", "use imperative mood (\"add\" not \"added\")"})
+	}
+
+	return errs
+}
+
+// Repair attempts to fix violations in pc in place: pairing the type with
+// its conventional emoji, re-casing an obviously past-tense verb to the
+// imperative, and truncating an overlong subject. It reports whether every
+// violation is now resolved; a false return (e.g. an unrecognized type)
+// means the caller should fall back to a second provider call instead.
+func Repair(pc *ParsedCommit, rules ValidationRules) bool {
+	if want, ok := emojiGuide[pc.Type]; ok {
+		pc.Emoji = want
+	}
+
+	if verb, rest, ok := splitFirstWord(pc.Subject); ok && isPastTense(verb) {
+		pc.Subject = imperativeOf(verb) + rest
+	}
+
+	if rules.MaxSubjectLen > 0 {
+		if overflow := utf8.RuneCountInString(pc.Header()) - rules.MaxSubjectLen; overflow > 0 {
+			subject := []rune(pc.Subject)
+			if len(subject) > overflow {
+				pc.Subject = strings.TrimSpace(string(subject[:len(subject)-overflow]))
+			}
+		}
+	}
+
+	return len(Validate(pc, rules)) == 0
+}
+
+// irregularPastToImperative maps the past-tense commit verbs genie sees most
+// often back to their imperative form.
+var irregularPastToImperative = map[string]string{
+	"added":       "add",
+	"fixed":       "fix",
+	"removed":     "remove",
+	"updated":     "update",
+	"changed":     "change",
+	"refactored":  "refactor",
+	"improved":    "improve",
+	"renamed":     "rename",
+	"moved":       "move",
+	"deleted":     "delete",
+	"created":     "create",
+	"implemented": "implement",
+	"resolved":    "resolve",
+	"cleaned":     "clean",
+	"reverted":    "revert",
+}
+
+func isPastTense(word string) bool {
+	_, ok := irregularPastToImperative[strings.ToLower(word)]
+	return ok
+}
+
+func imperativeOf(word string) string {
+	imp, ok := irregularPastToImperative[strings.ToLower(word)]
+	if !ok {
+		return word
+	}
+	if len(word) > 0 && word[0] >= 'A' && word[0] <= 'Z' {
+		return strings.ToUpper(imp[:1]) + imp[1:]
+	}
+	return imp
+}
+
+// splitFirstWord returns the first space-delimited word of s and everything
+// after it (including the separating space, so callers can concatenate).
+func splitFirstWord(s string) (word, rest string, ok bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", "", false
+	}
+	if idx := strings.IndexByte(s, ' '); idx >= 0 {
+		return s[:idx], s[idx:], true
+	}
+	return s, "", true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// feedbackRepairPrompt asks the provider to fix a commit message it already
+// produced, given the specific validation errors Validate/Repair couldn't
+// resolve on their own.
+func feedbackRepairPrompt(original string, errs []ValidationError) string {
+	var b strings.Builder
+	b.WriteString("The following commit message fails Conventional Commits validation:\n\n")
+	b.WriteString(original)
+	b.WriteString("\n\nValidation errors:\n")
+	for _, e := range errs {
+		fmt.Fprintf(&b, "- %s\n", e.Error())
+	}
+	b.WriteString("\nRewrite it to fix every error above while preserving its meaning. Respond with ONLY the corrected commit message, no explanations or quotes.")
+	return b.String()
+}
+
+// EnforceConventionalCommits runs each candidate through Parse/Validate and,
+// for anything non-compliant, tries Repair before falling back to a second
+// call to provider seeded with the specific validation errors. Candidates
+// that still can't be parsed at all are left untouched rather than dropped.
+func EnforceConventionalCommits(provider Provider, candidates []string, rules ValidationRules) []string {
+	out := make([]string, len(candidates))
+
+	for i, candidate := range candidates {
+		pc, err := Parse(candidate)
+		if err != nil {
+			out[i] = candidate
+			continue
+		}
+
+		errs := Validate(pc, rules)
+		if len(errs) == 0 {
+			out[i] = pc.String()
+			continue
+		}
+
+		if Repair(pc, rules) {
+			out[i] = pc.String()
+			continue
+		}
+		errs = Validate(pc, rules)
+
+		completion, err := provider.Generate(context.Background(), feedbackRepairPrompt(pc.String(), errs), GenerateOptions{})
+		if err != nil {
+			out[i] = pc.String()
+			continue
+		}
+		fixed := completion.Text
+
+		if repaired, err := Parse(strings.Trim(strings.TrimSpace(fixed), "\"'")); err == nil {
+			out[i] = repaired.String()
+		} else {
+			out[i] = pc.String()
+		}
+	}
+
+	return out
+}
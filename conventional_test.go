@@ -0,0 +1,212 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    ParsedCommit
+		wantErr bool
+	}{
+		{
+			name:    "header only",
+			message: "✨ feat(parser): add emoji support",
+			want: ParsedCommit{
+				Emoji:   "✨",
+				Type:    "feat",
+				Scope:   "parser",
+				Subject: "add emoji support",
+			},
+		},
+		{
+			name:    "breaking change marker",
+			message: "fix(api)!: remove the v1 endpoint",
+			want: ParsedCommit{
+				Type:     "fix",
+				Scope:    "api",
+				Breaking: true,
+				Subject:  "remove the v1 endpoint",
+			},
+		},
+		{
+			name: "body and footers",
+			message: "🐛 fix: stop double-staging changes\n\n" +
+				"Explains what broke and why.\n\n" +
+				"Refs: #42\nBREAKING CHANGE: removes the old flag",
+			want: ParsedCommit{
+				Emoji:   "🐛",
+				Type:    "fix",
+				Subject: "stop double-staging changes",
+				Body:    "Explains what broke and why.",
+				Footers: []Footer{
+					{Key: "Refs", Value: "#42"},
+					{Key: "BREAKING CHANGE", Value: "removes the old flag"},
+				},
+			},
+		},
+		{
+			name: "body without footers stays intact",
+			message: "docs: clarify install steps\n\n" +
+				"This is just prose, not a trailer block.",
+			want: ParsedCommit{
+				Type:    "docs",
+				Subject: "clarify install steps",
+				Body:    "This is just prose, not a trailer block.",
+			},
+		},
+		{
+			name:    "no match",
+			message: "WIP",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc, err := Parse(tt.message)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = nil error, want one", tt.message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.message, err)
+			}
+			if pc.Emoji != tt.want.Emoji || pc.Type != tt.want.Type || pc.Scope != tt.want.Scope ||
+				pc.Breaking != tt.want.Breaking || pc.Subject != tt.want.Subject || pc.Body != tt.want.Body {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.message, *pc, tt.want)
+			}
+			if len(pc.Footers) != len(tt.want.Footers) {
+				t.Fatalf("Parse(%q) footers = %+v, want %+v", tt.message, pc.Footers, tt.want.Footers)
+			}
+			for i, f := range pc.Footers {
+				if f != tt.want.Footers[i] {
+					t.Fatalf("Parse(%q) footer %d = %+v, want %+v", tt.message, i, f, tt.want.Footers[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseStringRoundTrip(t *testing.T) {
+	message := "✨ feat(parser): add emoji support\n\nSome body text.\n\nRefs: #7"
+	pc, err := Parse(message)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if got := pc.String(); got != message {
+		t.Fatalf("String() = %q, want %q", got, message)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	rules := DefaultRules()
+
+	tests := []struct {
+		name    string
+		pc      ParsedCommit
+		wantLen int
+	}{
+		{
+			name:    "fully compliant",
+			pc:      ParsedCommit{Emoji: "✨", Type: "feat", Subject: "add emoji support"},
+			wantLen: 0,
+		},
+		{
+			name:    "unknown type",
+			pc:      ParsedCommit{Emoji: "✨", Type: "bogus", Subject: "do a thing"},
+			wantLen: 1,
+		},
+		{
+			name:    "missing required leading emoji",
+			pc:      ParsedCommit{Type: "feat", Subject: "add emoji support"},
+			wantLen: 1,
+		},
+		{
+			name:    "mismatched emoji for type",
+			pc:      ParsedCommit{Emoji: "🐛", Type: "feat", Subject: "add emoji support"},
+			wantLen: 1,
+		},
+		{
+			name:    "past tense subject",
+			pc:      ParsedCommit{Emoji: "🐛", Type: "fix", Subject: "fixed the bug"},
+			wantLen: 1,
+		},
+		{
+			name:    "empty subject",
+			pc:      ParsedCommit{Emoji: "✨", Type: "feat", Subject: ""},
+			wantLen: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(&tt.pc, rules)
+			if len(errs) != tt.wantLen {
+				t.Fatalf("Validate(%+v) = %v (len %d), want len %d", tt.pc, errs, len(errs), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestValidateEmojiLeading(t *testing.T) {
+	rules := DefaultRules()
+	rules.EmojiLeading = false
+
+	pc := ParsedCommit{Type: "feat", Subject: "add emoji support ✨"}
+	if errs := Validate(&pc, rules); len(errs) != 0 {
+		t.Fatalf("Validate() with EmojiLeading=false and a trailing emoji = %v, want none", errs)
+	}
+
+	pc = ParsedCommit{Type: "feat", Subject: "add emoji support"}
+	if errs := Validate(&pc, rules); len(errs) == 0 {
+		t.Fatal("Validate() with EmojiLeading=false and no emoji at all = none, want a missing-emoji error")
+	}
+}
+
+func TestRepair(t *testing.T) {
+	rules := DefaultRules()
+
+	pc := &ParsedCommit{Type: "fix", Subject: "fixed the login bug"}
+	if ok := Repair(pc, rules); !ok {
+		t.Fatalf("Repair() = false, want true; pc = %+v", *pc)
+	}
+	if pc.Emoji != "🐛" {
+		t.Errorf("Repair() emoji = %q, want 🐛", pc.Emoji)
+	}
+	if pc.Subject != "fix the login bug" {
+		t.Errorf("Repair() subject = %q, want %q", pc.Subject, "fix the login bug")
+	}
+}
+
+func TestRepairTruncatesByRune(t *testing.T) {
+	rules := DefaultRules()
+	rules.MaxSubjectLen = 20
+
+	// Each "既" is a single rune but three bytes, so a byte-based truncation
+	// would land mid-rune; this subject is deliberately long enough to force
+	// truncation under the 20-rune limit.
+	pc := &ParsedCommit{Type: "feat", Emoji: "✨", Subject: "既存のコミットメッセージ生成ロジックを大幅に改善する"}
+	Repair(pc, rules)
+
+	for i, r := range pc.Subject {
+		if r == utf8.RuneError {
+			t.Fatalf("Repair() produced invalid UTF-8 at byte %d: %q", i, pc.Subject)
+		}
+	}
+	if errs := Validate(pc, rules); len(errs) != 0 {
+		t.Fatalf("Repair() left violations: %v; pc.Subject = %q", errs, pc.Subject)
+	}
+}
+
+func TestEnforceConventionalCommitsLeavesUnparseableCandidatesUntouched(t *testing.T) {
+	out := EnforceConventionalCommits(nil, []string{"WIP"}, DefaultRules())
+	if len(out) != 1 || out[0] != "WIP" {
+		t.Fatalf("EnforceConventionalCommits() = %v, want [\"WIP\"]", out)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// diffTokenThreshold is the approximate token budget under which a diff is
+// sent to the provider as-is instead of being chunked and summarized.
+// Token counts are estimated, not exact: roughly 4 bytes per token is close
+// enough to decide "does this still fit in a prompt" without a real
+// tokenizer dependency.
+const diffTokenThreshold = 6000
+
+// defaultMaxDiffLines mirrors the 150-line cutoff used by similar
+// commit-suggestion tools; --max-diff-lines overrides it.
+const defaultMaxDiffLines = 150
+
+func approxTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// fileDiff is one file's hunk(s) out of a larger `git diff` output.
+type fileDiff struct {
+	Path string
+	Text string
+}
+
+var fileHeaderPattern = regexp.MustCompile(`^diff --git a/(.+?) b/(.+)$`)
+
+// splitDiffByFile breaks a unified diff into one fileDiff per
+// "diff --git" section, keeping each file's hunks intact.
+func splitDiffByFile(diff string) []fileDiff {
+	var files []fileDiff
+	var current *fileDiff
+
+	for _, line := range strings.Split(diff, "\n") {
+		if m := fileHeaderPattern.FindStringSubmatch(line); m != nil {
+			if current != nil {
+				files = append(files, *current)
+			}
+			current = &fileDiff{Path: m[2]}
+		}
+		if current == nil {
+			continue
+		}
+		if current.Text != "" {
+			current.Text += "\n"
+		}
+		current.Text += line
+	}
+	if current != nil {
+		files = append(files, *current)
+	}
+	return files
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ `)
+
+// splitFileByHunks further breaks one file's diff into its individual @@
+// hunks, used when a single file's diff is still too large to summarize in
+// one call. The file header (everything before the first hunk) is repeated
+// on each piece so the model still has path/mode context.
+func splitFileByHunks(f fileDiff) []fileDiff {
+	lines := strings.Split(f.Text, "\n")
+
+	headerEnd := len(lines)
+	for i, line := range lines {
+		if hunkHeaderPattern.MatchString(line) {
+			headerEnd = i
+			break
+		}
+	}
+	header := strings.Join(lines[:headerEnd], "\n")
+
+	var hunks []fileDiff
+	var current *fileDiff
+	for _, line := range lines[headerEnd:] {
+		if hunkHeaderPattern.MatchString(line) {
+			if current != nil {
+				hunks = append(hunks, *current)
+			}
+			current = &fileDiff{Path: f.Path, Text: header + "\n" + line}
+			continue
+		}
+		if current != nil {
+			current.Text += "\n" + line
+		}
+	}
+	if current != nil {
+		hunks = append(hunks, *current)
+	}
+	if len(hunks) == 0 {
+		return []fileDiff{f}
+	}
+	return hunks
+}
+
+// summarizeOnce asks provider to describe what changed and why in a single
+// file (or hunk) diff, in a sentence or two.
+func summarizeOnce(ctx context.Context, provider Provider, path, diffText string) (string, error) {
+	prompt := fmt.Sprintf(`Summarize what changed and why in this diff, in 1-2 sentences. Be specific about behavior, not line-by-line.
+
+File: %s
+
+%s`, path, diffText)
+
+	completion, err := provider.Generate(ctx, prompt, GenerateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("summarizing %s: %w", path, err)
+	}
+	return strings.TrimSpace(completion.Text), nil
+}
+
+// FileSummary is one file's distilled change description, produced when a
+// diff is too large to hand the provider in full.
+type FileSummary struct {
+	Path    string
+	Summary string
+}
+
+// summarizeFile summarizes one file's diff, falling back to per-hunk
+// summarization (and joining the results) if the file's own diff is still
+// over diffTokenThreshold on its own.
+func summarizeFile(ctx context.Context, provider Provider, f fileDiff) (string, error) {
+	if approxTokens(f.Text) <= diffTokenThreshold {
+		return summarizeOnce(ctx, provider, f.Path, f.Text)
+	}
+
+	var hunkSummaries []string
+	for _, hunk := range splitFileByHunks(f) {
+		summary, err := summarizeOnce(ctx, provider, f.Path, hunk.Text)
+		if err != nil {
+			return "", err
+		}
+		hunkSummaries = append(hunkSummaries, summary)
+	}
+	return strings.Join(hunkSummaries, " "), nil
+}
+
+// getGitDiffStat returns `git diff --stat` output matching changesType, used
+// as compact context alongside per-file summaries once the full diff has
+// been chunked away.
+func getGitDiffStat(changesType string) (string, error) {
+	args := []string{"diff", "--stat"}
+	if changesType == "staged" {
+		args = []string{"diff", "--cached", "--stat"}
+	}
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// PreparedDiff is what prepareDiff hands to the prompt builder: either the
+// original diff verbatim, or a stat + per-file-summary stand-in for it.
+type PreparedDiff struct {
+	Text      string
+	Chunked   bool
+	Summaries []FileSummary
+}
+
+// prepareDiff measures diff against maxDiffLines and diffTokenThreshold; if
+// it fits, it's returned unchanged. Otherwise it's split by file (and, for
+// any file still too large, by hunk), each piece is summarized by provider,
+// and the per-file summaries plus `git diff --stat` are returned in its
+// place so the final prompt always fits.
+func prepareDiff(ctx context.Context, provider Provider, diff, changesType string, maxDiffLines int) (PreparedDiff, error) {
+	lineCount := strings.Count(diff, "\n") + 1
+	if lineCount <= maxDiffLines && approxTokens(diff) <= diffTokenThreshold {
+		return PreparedDiff{Text: diff}, nil
+	}
+
+	files := splitDiffByFile(diff)
+	if len(files) == 0 {
+		// Not a unified diff we know how to split (e.g. the untracked-files
+		// summary) - fall back to sending it as-is rather than losing it.
+		return PreparedDiff{Text: diff}, nil
+	}
+
+	summaries := make([]FileSummary, 0, len(files))
+	for _, f := range files {
+		summary, err := summarizeFile(ctx, provider, f)
+		if err != nil {
+			return PreparedDiff{}, err
+		}
+		summaries = append(summaries, FileSummary{Path: f.Path, Summary: summary})
+	}
+
+	stat, err := getGitDiffStat(changesType)
+	if err != nil {
+		stat = ""
+	}
+
+	var b strings.Builder
+	b.WriteString("The diff was too large to include in full, so it was summarized per file.\n\n")
+	if stat != "" {
+		b.WriteString("git diff --stat:\n")
+		b.WriteString(stat)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Per-file summaries:\n")
+	for _, s := range summaries {
+		fmt.Fprintf(&b, "- %s: %s\n", s.Path, s.Summary)
+	}
+
+	return PreparedDiff{Text: b.String(), Chunked: true, Summaries: summaries}, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	geminiURL          = "https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent"
+	geminiDefaultModel = "gemini-1.5-flash-latest"
+)
+
+type GeminiRequest struct {
+	Contents []Content `json:"contents"`
+}
+
+type Content struct {
+	Parts []Part `json:"parts"`
+}
+
+type Part struct {
+	Text string `json:"text"`
+}
+
+type GeminiResponse struct {
+	Candidates []Candidate `json:"candidates"`
+	Error      *ErrorInfo  `json:"error,omitempty"`
+}
+
+type Candidate struct {
+	Content ContentResponse `json:"content"`
+}
+
+type ContentResponse struct {
+	Parts []PartResponse `json:"parts"`
+}
+
+type PartResponse struct {
+	Text string `json:"text"`
+}
+
+type ErrorInfo struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// geminiProvider talks to Google's Gemini API.
+type geminiProvider struct {
+	apiKey string
+	model  string
+}
+
+func newGeminiProvider() (Provider, error) {
+	apiKey, err := requireEnv("GOOGLE_AI_TOKEN", "Get your API key from: https://aistudio.google.com/apikey\n   Then run: export GOOGLE_AI_TOKEN=your_api_key_here")
+	if err != nil {
+		return nil, err
+	}
+
+	model := os.Getenv("GENIE_GEMINI_MODEL")
+	if model == "" {
+		model = geminiDefaultModel
+	}
+
+	return &geminiProvider{apiKey: apiKey, model: model}, nil
+}
+
+func (p *geminiProvider) Name() string { return "gemini" }
+
+func (p *geminiProvider) Generate(ctx context.Context, prompt string, opts GenerateOptions) (Completion, error) {
+	model := p.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+
+	reqBody := GeminiRequest{
+		Contents: []Content{
+			{Parts: []Part{{Text: prompt}}},
+		},
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return Completion{}, err
+	}
+
+	url := fmt.Sprintf(geminiURL, model) + "?key=" + p.apiKey
+
+	text, err := withRetry(ctx, func() (string, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		client := &http.Client{Timeout: generateTimeout(opts)}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+
+		var geminiResp GeminiResponse
+		if err := json.Unmarshal(body, &geminiResp); err != nil {
+			return "", err
+		}
+
+		if geminiResp.Error != nil {
+			return "", fmt.Errorf("gemini API error: %s", geminiResp.Error.Message)
+		}
+		if len(geminiResp.Candidates) == 0 {
+			return "", fmt.Errorf("no response from Gemini API")
+		}
+		if len(geminiResp.Candidates[0].Content.Parts) == 0 {
+			return "", fmt.Errorf("empty response from Gemini API")
+		}
+
+		return strings.TrimSpace(geminiResp.Candidates[0].Content.Parts[0].Text), nil
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	return Completion{Text: text}, nil
+}
+
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed i18n/*.json
+var localeFS embed.FS
+
+// defaultLocale is the catalog genie falls back to when --lang/$LANG/$LC_ALL
+// names a language with no i18n/<lang>.json shipped.
+const defaultLocale = "en"
+
+// Messages holds every user-facing string genie prints, loaded from an
+// i18n/<lang>.json catalog. Fields that take a value use the same verbs
+// (%s, %d, %v) as the printf-style helpers in main.go.
+type Messages struct {
+	AnalyzingStaged      string `json:"analyzing_staged"`
+	AnalyzingUnstaged    string `json:"analyzing_unstaged"`
+	TipStageChanges      string `json:"tip_stage_changes"`
+	AnalyzingUntracked   string `json:"analyzing_untracked"`
+	TipStageFiles        string `json:"tip_stage_files"`
+	NoChanges            string `json:"no_changes"`
+	ContextLine          string `json:"context_line"`
+	DiffTooLarge         string `json:"diff_too_large"`
+	SelectedCommit       string `json:"selected_commit"`
+	Committed            string `json:"committed"`
+	CopiedClipboard      string `json:"copied_clipboard"`
+	ClipboardUnavailable string `json:"clipboard_unavailable"`
+	ErrNotGitRepo        string `json:"err_not_git_repo"`
+	ErrProvider          string `json:"err_provider"`
+	ErrGettingDiff       string `json:"err_getting_diff"`
+	ErrGettingStatus     string `json:"err_getting_status"`
+	ErrReadingConfig     string `json:"err_reading_config"`
+	ErrSummarizingDiff   string `json:"err_summarizing_diff"`
+	ErrGeneratingCommit  string `json:"err_generating_commit"`
+	ErrRunningCommit     string `json:"err_running_commit"`
+	ErrPicker            string `json:"err_picker"`
+}
+
+// Localizer resolves genie's user-facing strings for one language.
+type Localizer struct {
+	lang string
+	Messages
+}
+
+// NewLocalizer loads the catalog for lang, falling back to defaultLocale
+// (shipped alongside the binary, so this only fails if the embed itself is
+// broken) when lang has no catalog of its own.
+func NewLocalizer(lang string) (*Localizer, error) {
+	resolved := lang
+	if resolved == "" {
+		resolved = defaultLocale
+	}
+
+	msgs, err := loadCatalog(resolved)
+	if err != nil {
+		if resolved == defaultLocale {
+			return nil, err
+		}
+		msgs, err = loadCatalog(defaultLocale)
+		if err != nil {
+			return nil, err
+		}
+		resolved = defaultLocale
+	}
+
+	return &Localizer{lang: resolved, Messages: msgs}, nil
+}
+
+func loadCatalog(lang string) (Messages, error) {
+	data, err := localeFS.ReadFile(fmt.Sprintf("i18n/%s.json", lang))
+	if err != nil {
+		return Messages{}, fmt.Errorf("no catalog for locale %q: %w", lang, err)
+	}
+
+	var msgs Messages
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return Messages{}, fmt.Errorf("parsing i18n/%s.json: %w", lang, err)
+	}
+	return msgs, nil
+}
+
+// resolveLang picks the UI language: --lang wins, then LC_ALL, then LANG
+// (POSIX-style, e.g. "es_ES.UTF-8" - only the language subtag is used),
+// falling back to defaultLocale.
+func resolveLang(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	for _, env := range []string{"LC_ALL", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			return normalizeLangTag(v)
+		}
+	}
+	return defaultLocale
+}
+
+func normalizeLangTag(tag string) string {
+	tag = strings.SplitN(tag, ".", 2)[0]
+	tag = strings.SplitN(tag, "_", 2)[0]
+	return strings.ToLower(tag)
+}
+
+// messageLangInstructions, when messageLang is set, tells the model to write
+// the subject/body in that natural language while keeping the conventional
+// commit type token (feat, fix, ...) in English so EnforceConventionalCommits
+// can still parse and validate it.
+func messageLangInstructions(messageLang string) string {
+	if messageLang == "" {
+		return ""
+	}
+	return fmt.Sprintf(`
+
+🌍 OUTPUT LANGUAGE:
+Write the subject and body text in %q. Keep the conventional commit type
+token itself (feat, fix, docs, ...) and any footer keys (Refs, Signed-off-by,
+BREAKING CHANGE, ...) in English - only the human-readable text changes
+language.`, messageLang)
+}
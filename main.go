@@ -1,22 +1,18 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
-	"time"
 )
 
 const (
-	appName   = "genie"
-	version   = "1.0.0"
-	geminiURL = "https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash-latest:generateContent"
+	appName = "genie"
+	version = "1.0.0"
 )
 
 // Color constants
@@ -31,40 +27,6 @@ const (
 	colorCyan   = "\033[36m" // Highlights
 )
 
-type GeminiRequest struct {
-	Contents []Content `json:"contents"`
-}
-
-type Content struct {
-	Parts []Part `json:"parts"`
-}
-
-type Part struct {
-	Text string `json:"text"`
-}
-
-type GeminiResponse struct {
-	Candidates []Candidate `json:"candidates"`
-	Error      *ErrorInfo  `json:"error,omitempty"`
-}
-
-type Candidate struct {
-	Content ContentResponse `json:"content"`
-}
-
-type ContentResponse struct {
-	Parts []PartResponse `json:"parts"`
-}
-
-type PartResponse struct {
-	Text string `json:"text"`
-}
-
-type ErrorInfo struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
-
 // Helper functions for colored output
 func grayf(format string, args ...interface{}) {
 	fmt.Printf(colorGray+format+colorReset, args...)
@@ -83,94 +45,178 @@ func cyanf(format string, args ...interface{}) {
 }
 
 func main() {
-	var context string
-
-	// Parse arguments
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "--version", "-v":
-			fmt.Printf("%s v%s\n", appName, version)
-			return
-		case "--help", "-h":
-			printHelp()
-			return
-		default:
-			// If first argument is not a flag, treat it as context
-			// Join all arguments as context (in case of spaces)
-			context = strings.Join(os.Args[1:], " ")
-		}
+	countFlag := flag.Int("count", 3, "number of candidate commit messages to generate")
+	flag.IntVar(countFlag, "n", 3, "shorthand for --count")
+	commitFlag := flag.Bool("commit", false, "run 'git commit -m' with the selected message instead of copying to clipboard")
+	flag.BoolVar(commitFlag, "c", false, "shorthand for --commit")
+	longFlag := flag.Bool("long", false, "generate a wrapped body and git trailers (Refs, Source-Link, Signed-off-by, BREAKING CHANGE) in addition to the subject line")
+	flag.BoolVar(longFlag, "body", false, "shorthand for --long")
+	signoffFlag := flag.Bool("signoff", false, "add a Signed-off-by trailer (implied if user.signingkey is configured); only applies with --long")
+	flag.BoolVar(signoffFlag, "s", false, "shorthand for --signoff")
+	providerFlag := flag.String("provider", "", "LLM backend to use: gemini, openai, anthropic, or ollama (default gemini, or $GENIE_PROVIDER)")
+	flag.StringVar(providerFlag, "p", "", "shorthand for --provider")
+	maxDiffLinesFlag := flag.Int("max-diff-lines", defaultMaxDiffLines, "diffs longer than this switch to per-file summarization instead of erroring out")
+	verboseFlag := flag.Bool("verbose", false, "print the intermediate per-file summaries when a diff is too large to send in full")
+	langFlag := flag.String("lang", "", "UI language for genie's own output (default $LC_ALL, then $LANG, then en)")
+	messageLangFlag := flag.String("message-lang", "", "natural language for the generated commit subject/body, e.g. es, ja, de (default: English)")
+	versionFlag := flag.Bool("version", false, "show version information")
+	flag.BoolVar(versionFlag, "v", false, "shorthand for --version")
+	flag.Usage = printHelp
+	flag.Parse()
+
+	if *versionFlag {
+		fmt.Printf("%s v%s\n", appName, version)
+		return
+	}
+
+	ctx := context.Background()
+
+	// Any remaining positional arguments are joined into the context string
+	// (in case it contains spaces).
+	context := strings.Join(flag.Args(), " ")
+
+	count := *countFlag
+	if count < 1 {
+		count = 1
+	}
+
+	loc, err := NewLocalizer(resolveLang(*langFlag))
+	if err != nil {
+		redf("❌ Error loading translations: %v\n", err)
+		os.Exit(1)
 	}
 
 	// Check if we're in a git repository
 	if !isGitRepo() {
-		redf("❌ Error: Not a git repository\n")
+		redf(loc.ErrNotGitRepo)
 		os.Exit(1)
 	}
 
-	// Get API key from environment
-	apiKey := os.Getenv("GOOGLE_AI_TOKEN")
-	if apiKey == "" {
-		redf("❌ Error: GOOGLE_AI_TOKEN environment variable not set\n")
-		redf("   Get your API key from: https://aistudio.google.com/apikey\n")
-		redf("   Then run: export GOOGLE_AI_TOKEN=your_api_key_here\n")
+	// Resolve which LLM backend to talk to
+	provider, err := NewProvider(resolveProviderName(*providerFlag))
+	if err != nil {
+		redf(loc.ErrProvider, err)
 		os.Exit(1)
 	}
 
 	if context != "" {
-		grayf("📝 Context: \"%s\"\n", context)
+		grayf(loc.ContextLine, context)
 	}
 
 	// Get git diff and determine what type of changes we're analyzing
 	diff, changesType, err := getGitDiff()
 	if err != nil {
-		redf("❌ Error getting git diff: %v\n", err)
+		redf(loc.ErrGettingDiff, err)
 		os.Exit(1)
 	}
 
 	if strings.TrimSpace(diff) == "" {
-		fmt.Println("✨ No changes detected. Nothing to commit!")
+		fmt.Print(loc.NoChanges)
 		return
 	}
 
 	// Show what we're analyzing - more subtle
 	switch changesType {
 	case "staged":
-		grayf("Analyzing staged changes...\n")
+		grayf(loc.AnalyzingStaged)
 	case "unstaged":
-		grayf("No staged changes, analyzing unstaged changes...\n")
-		grayf("💡 Tip: Run 'git add .' to stage changes first\n")
+		grayf(loc.AnalyzingUnstaged)
+		grayf(loc.TipStageChanges)
 	case "untracked":
-		grayf("Analyzing untracked files...\n")
-		grayf("💡 Tip: Run 'git add .' to stage files first\n")
+		grayf(loc.AnalyzingUntracked)
+		grayf(loc.TipStageFiles)
 	}
 
 	// Get git status for context
 	status, err := getGitStatus()
 	if err != nil {
-		redf("❌ Error getting git status: %v\n", err)
+		redf(loc.ErrGettingStatus, err)
+		os.Exit(1)
+	}
+
+	rules, err := LoadConfig(configFileName)
+	if err != nil {
+		redf(loc.ErrReadingConfig, configFileName, err)
+		os.Exit(1)
+	}
+
+	// Learn this repository's own conventions from its commit history (and
+	// .gitmessage/CONTRIBUTING.md/commitlint.config.js, if present), caching
+	// the result in .git/genie-style.json. A repo with no usable history
+	// just falls back to the generic rules above rather than failing here.
+	profile, err := LoadStyleProfile()
+	if err != nil {
+		profile = StyleProfile{}
+	}
+	rules = profile.ApplyToRules(rules)
+
+	// Large diffs get summarized per file (and per hunk, if a single file is
+	// still too big) instead of being shoved into the prompt whole
+	prepared, err := prepareDiff(ctx, provider, diff, changesType, *maxDiffLinesFlag)
+	if err != nil {
+		redf(loc.ErrSummarizingDiff, err)
+		os.Exit(1)
+	}
+	if prepared.Chunked {
+		grayf(loc.DiffTooLarge, len(prepared.Summaries))
+		if *verboseFlag {
+			for _, s := range prepared.Summaries {
+				grayf("   %s: %s\n", s.Path, s.Summary)
+			}
+		}
+	}
+
+	// Generate N candidate commit messages, validated/repaired against the
+	// Conventional Commits rules (and, in --long mode, enriched with a
+	// wrapped body and local trailers), and let the user pick one
+	generate := func() ([]string, error) {
+		candidates, err := generateCommitMessages(provider, prepared.Text, status, context, changesType, count, *longFlag, *messageLangFlag, profile)
+		if err != nil {
+			return nil, err
+		}
+		candidates = EnforceConventionalCommits(provider, candidates, rules)
+		if *longFlag {
+			candidates = applyTrailersToAll(candidates, *signoffFlag)
+		}
+		return candidates, nil
+	}
+
+	candidates, err := generate()
+	if err != nil {
+		redf(loc.ErrGeneratingCommit, err)
 		os.Exit(1)
 	}
 
-	// Generate commit message
-	commitMsg, err := generateCommitMessage(apiKey, diff, status, context, changesType)
+	result, err := runPicker(candidates, *commitFlag, *longFlag, generate)
 	if err != nil {
-		redf("❌ Error generating commit message: %v\n", err)
+		redf(loc.ErrPicker, err)
 		os.Exit(1)
 	}
 
-	// Display the generated commit message - make this prominent
+	// Display the selected commit message - make this prominent
 	fmt.Println()
-	boldf("✨ Generated commit message:\n")
+	boldf(loc.SelectedCommit)
 	cyanf("┌─────────────────────────────────────────────────────────────────\n")
-	cyanf("│ %s\n", commitMsg)
+	for _, line := range strings.Split(result.message, "\n") {
+		cyanf("│ %s\n", line)
+	}
 	cyanf("└─────────────────────────────────────────────────────────────────\n")
 
+	if result.commit {
+		if err := commitChanges(result.message, changesType); err != nil {
+			redf(loc.ErrRunningCommit, err)
+			os.Exit(1)
+		}
+		grayf(loc.Committed)
+		return
+	}
+
 	// Copy to clipboard
-	err = copyToClipboard(commitMsg)
+	err = copyToClipboard(result.message)
 	if err != nil {
-		grayf("📋 Could not copy to clipboard: %v\n", err)
+		grayf(loc.ClipboardUnavailable, err)
 	} else {
-		grayf("📋 Copied to clipboard\n")
+		grayf(loc.CopiedClipboard)
 	}
 }
 
@@ -182,23 +228,45 @@ USAGE:
     %s [CONTEXT]
 
 OPTIONS:
-    -h, --help      Show this help message
-    -v, --version   Show version information
+    -h, --help           Show this help message
+    -v, --version        Show version information
+    -n, --count N        Number of candidate commit messages to generate (default 3)
+    -c, --commit         Run 'git commit -m' with the selected message
+                        instead of copying it to the clipboard
+        --long, --body   Also generate a wrapped body and git trailers
+                        (Refs, Source-Link, Signed-off-by, BREAKING CHANGE)
+    -s, --signoff        Add a Signed-off-by trailer (implied if
+                        user.signingkey is configured); only with --long
+    -p, --provider NAME  LLM backend: gemini, openai, anthropic, ollama
+                        (default gemini, or $GENIE_PROVIDER)
+        --max-diff-lines N  Diffs longer than this are summarized per file
+                        instead of sent in full (default %d)
+        --verbose        Print the intermediate per-file summaries when a
+                        diff is too large to send in full
+        --lang LANG      UI language for genie's own output
+                        (default $LC_ALL, then $LANG, then en)
+        --message-lang LANG  Natural language for the generated commit
+                        subject/body, e.g. es, ja, de (default: English)
 
 ARGUMENTS:
     CONTEXT         Optional context to help generate better commit messages
                    (e.g., "changes from Bot API 9.0", "refactor for performance")
 
 SETUP:
-    1. Get your Gemini API key from: https://aistudio.google.com/apikey
-    2. Set the environment variable: export GOOGLE_AI_TOKEN=your_api_key_here
+    1. Pick a provider and set its API key (default is gemini):
+         gemini    - https://aistudio.google.com/apikey  -> GOOGLE_AI_TOKEN
+         openai    - https://platform.openai.com/api-keys -> OPENAI_API_KEY
+         anthropic - https://console.anthropic.com/       -> ANTHROPIC_API_KEY
+         ollama    - a local server, no key needed         -> OLLAMA_HOST (optional)
+    2. Select it with --provider/-p, or export GENIE_PROVIDER
     3. Run %s in any git repository with changes
 
 DESCRIPTION:
     %s analyzes your git changes and generates perfect commit messages
-    using Google's Gemini AI. It follows conventional commit standards,
-    includes relevant emojis, and automatically copies the message to
-    your clipboard for easy use.
+    using an LLM of your choice (Gemini, OpenAI, Anthropic, or a local
+    Ollama model). It follows conventional commit standards, includes
+    relevant emojis, and generates several candidates you can flip
+    through in an interactive picker before using one.
 
     The tool prioritizes staged changes (files added with 'git add'), but
     if no staged changes are found, it will analyze all unstaged changes
@@ -207,14 +275,25 @@ DESCRIPTION:
     You can optionally provide context to help generate more accurate
     commit messages when you have many related changes.
 
+    In the picker: ↑/↓ to move, Enter or y to accept, e to edit the
+    highlighted candidate, r to regenerate the whole set, q to quit.
+    Accepting copies the message to your clipboard, unless --commit was
+    passed, in which case genie runs 'git commit -m' with it directly.
+
 EXAMPLES:
     %s                              # Generate commit message for changes
     %s "Bot API 9.0 migration"      # Generate with context
     %s "performance improvements"   # Generate with context
+    %s -n 5                        # Generate 5 candidates to pick from
+    %s -c                          # Commit directly instead of copying
+    %s --long                      # Generate a wrapped body and trailers too
+    %s --long -s                   # Also add a Signed-off-by trailer
+    %s -p ollama                   # Use a local Ollama model instead of Gemini
+    %s --message-lang ja           # Write the commit message in Japanese
     %s --version                   # Show version
     %s --help                     # Show this help
 
-`, appName, version, appName, appName, appName, appName, appName, appName, appName, appName, appName)
+`, appName, version, appName, appName, defaultMaxDiffLines, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName)
 }
 
 func isGitRepo() bool {
@@ -283,7 +362,11 @@ func getGitStatus() (string, error) {
 	return string(output), nil
 }
 
-func generateCommitMessage(apiKey, diff, status, context, changesType string) (string, error) {
+// candidateSeparator is the delimiter we ask the provider to place between
+// alternative commit messages so a single response can hold N candidates.
+const candidateSeparator = "---"
+
+func generateCommitMessages(provider Provider, diff, status, userContext, changesType string, count int, long bool, messageLang string, profile StyleProfile) ([]string, error) {
 	changesDescription := ""
 	switch changesType {
 	case "staged":
@@ -362,13 +445,13 @@ EMOJI SELECTION GUIDE:
 🌍 global: global changes, configurations`)
 
 	// Add context section if provided
-	if context != "" {
+	if userContext != "" {
 		promptBuilder.WriteString(fmt.Sprintf(`
 
 🎯 DEVELOPER CONTEXT:
 The developer provided this context: "%s"
 
-This context is CRITICAL - use it to understand the broader purpose and ensure your commit message accurately reflects the intended changes within this context. The context should guide your interpretation of what these technical changes accomplish at a higher level.`, context))
+This context is CRITICAL - use it to understand the broader purpose and ensure your commit message accurately reflects the intended changes within this context. The context should guide your interpretation of what these technical changes accomplish at a higher level.`, userContext))
 	}
 
 	promptBuilder.WriteString(fmt.Sprintf(`
@@ -383,11 +466,15 @@ Git Diff/Changes:
 %s
 
 🎯 RESPONSE FORMAT:
-Respond with ONLY the commit message including emoji. No explanations, quotes, or additional text.
+Generate %d distinct candidate commit messages for the SAME change, each exploring a
+different valid phrasing, scope, or type/emoji choice. Respond with ONLY the commit
+messages, one per candidate, separated by a line containing exactly "%s" and nothing
+else. No numbering, no explanations, no quotes, no additional text.
+%s%s%s
 
 EXAMPLES OF EXCELLENT COMMIT MESSAGES:
 ✨ feat(auth): add OAuth2 Google integration
-🐛 fix(api): handle null response in user endpoint  
+🐛 fix(api): handle null response in user endpoint
 ♻️ refactor(utils): simplify date formatting logic
 📝 docs: update API authentication guide
 🔧 chore(deps): update React to v18.2.0
@@ -395,65 +482,59 @@ EXAMPLES OF EXCELLENT COMMIT MESSAGES:
 🎨 ui: improve button hover animations
 🔒 security: sanitize user input in forms
 
-Generate the perfect commit message now:`, changesDescription, status, diff))
+Generate the %d perfect commit messages now:`, changesDescription, status, diff, count, candidateSeparator, longModeInstructions(long), messageLangInstructions(messageLang), houseStyleInstructions(profile), count))
 
-	reqBody := GeminiRequest{
-		Contents: []Content{
-			{
-				Parts: []Part{
-					{Text: promptBuilder.String()},
-				},
-			},
-		},
-	}
-
-	jsonBody, err := json.Marshal(reqBody)
+	completion, err := provider.Generate(context.Background(), promptBuilder.String(), GenerateOptions{})
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("%s: %w", provider.Name(), err)
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("POST", geminiURL+"?key="+apiKey, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", err
+	candidates := splitCandidates(completion.Text)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no usable commit message in %s response", provider.Name())
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	var geminiResp GeminiResponse
-	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		return "", err
-	}
-
-	if geminiResp.Error != nil {
-		return "", fmt.Errorf("API error: %s", geminiResp.Error.Message)
-	}
+	return candidates, nil
+}
 
-	if len(geminiResp.Candidates) == 0 {
-		return "", fmt.Errorf("no response from Gemini API")
+// splitCandidates breaks a raw provider response into individual commit
+// message candidates along the candidateSeparator lines requested in the
+// prompt, trimming whitespace and surrounding quotes from each one.
+func splitCandidates(raw string) []string {
+	parts := strings.Split(raw, "\n"+candidateSeparator+"\n")
+	var candidates []string
+	for _, part := range parts {
+		msg := strings.TrimSpace(part)
+		msg = strings.Trim(msg, "\"'")
+		msg = strings.TrimSpace(msg)
+		if msg != "" {
+			candidates = append(candidates, msg)
+		}
 	}
+	return candidates
+}
 
-	if len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("empty response from Gemini API")
+// commitChanges stages whatever genie analyzed - "git add -u" for unstaged
+// tracked changes, "git add -A" for untracked files, nothing when it was
+// already staged - then runs `git commit -m <message>`. Used when --commit
+// is set so the picker can go straight from selection to a real commit
+// instead of only populating the clipboard.
+func commitChanges(message, changesType string) error {
+	switch changesType {
+	case "unstaged":
+		if err := exec.Command("git", "add", "-u").Run(); err != nil {
+			return fmt.Errorf("staging changes: %w", err)
+		}
+	case "untracked":
+		if err := exec.Command("git", "add", "-A").Run(); err != nil {
+			return fmt.Errorf("staging changes: %w", err)
+		}
 	}
 
-	commitMsg := strings.TrimSpace(geminiResp.Candidates[0].Content.Parts[0].Text)
-
-	// Clean up the response (remove quotes if present)
-	commitMsg = strings.Trim(commitMsg, "\"'")
-
-	return commitMsg, nil
+	cmd := exec.Command("git", "commit", "-m", message)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
 }
 
 func copyToClipboard(text string) error {
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCandidates(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{
+			name: "single candidate",
+			raw:  "✨ feat: add thing",
+			want: []string{"✨ feat: add thing"},
+		},
+		{
+			name: "multiple candidates",
+			raw:  "✨ feat: add thing\n---\n🐛 fix: fix thing",
+			want: []string{"✨ feat: add thing", "🐛 fix: fix thing"},
+		},
+		{
+			name: "trims whitespace and quotes",
+			raw:  "  \"✨ feat: add thing\"  \n---\n'🐛 fix: fix thing'",
+			want: []string{"✨ feat: add thing", "🐛 fix: fix thing"},
+		},
+		{
+			name: "skips empty parts",
+			raw:  "✨ feat: add thing\n---\n\n---\n🐛 fix: fix thing",
+			want: []string{"✨ feat: add thing", "🐛 fix: fix thing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitCandidates(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCandidates(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
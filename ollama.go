@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	ollamaDefaultHost  = "http://localhost:11434"
+	ollamaDefaultModel = "llama3"
+)
+
+type ollamaRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Response string `json:"response"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ollamaProvider talks to a local Ollama server, for users running a model
+// on their own machine with no API key at all.
+type ollamaProvider struct {
+	host  string
+	model string
+}
+
+func newOllamaProvider() (Provider, error) {
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		host = ollamaDefaultHost
+	}
+
+	model := os.Getenv("GENIE_OLLAMA_MODEL")
+	if model == "" {
+		model = ollamaDefaultModel
+	}
+
+	return &ollamaProvider{host: host, model: model}, nil
+}
+
+func (p *ollamaProvider) Name() string { return "ollama" }
+
+func (p *ollamaProvider) Generate(ctx context.Context, prompt string, opts GenerateOptions) (Completion, error) {
+	model := p.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+
+	jsonBody, err := json.Marshal(ollamaRequest{Model: model, Prompt: prompt, Stream: false})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	text, err := withRetry(ctx, func() (string, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(p.host, "/")+"/api/generate", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		client := &http.Client{Timeout: generateTimeout(opts)}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+
+		var ollamaResp ollamaResponse
+		if err := json.Unmarshal(body, &ollamaResp); err != nil {
+			return "", err
+		}
+
+		if ollamaResp.Error != "" {
+			return "", fmt.Errorf("ollama error: %s", ollamaResp.Error)
+		}
+		if strings.TrimSpace(ollamaResp.Response) == "" {
+			return "", fmt.Errorf("empty response from Ollama (is %s running and %q pulled?)", p.host, model)
+		}
+
+		return strings.TrimSpace(ollamaResp.Response), nil
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	return Completion{Text: text}, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	openAIURL          = "https://api.openai.com/v1/chat/completions"
+	openAIDefaultModel = "gpt-4o-mini"
+)
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// openAIProvider talks to the OpenAI chat-completions API, for users who
+// already have an OpenAI key and no Google account.
+type openAIProvider struct {
+	apiKey string
+	model  string
+}
+
+func newOpenAIProvider() (Provider, error) {
+	apiKey, err := requireEnv("OPENAI_API_KEY", "Get your API key from: https://platform.openai.com/api-keys\n   Then run: export OPENAI_API_KEY=your_api_key_here")
+	if err != nil {
+		return nil, err
+	}
+
+	model := os.Getenv("GENIE_OPENAI_MODEL")
+	if model == "" {
+		model = openAIDefaultModel
+	}
+
+	return &openAIProvider{apiKey: apiKey, model: model}, nil
+}
+
+func (p *openAIProvider) Name() string { return "openai" }
+
+func (p *openAIProvider) Generate(ctx context.Context, prompt string, opts GenerateOptions) (Completion, error) {
+	model := p.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+
+	jsonBody, err := json.Marshal(openAIRequest{
+		Model:    model,
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	text, err := withRetry(ctx, func() (string, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", openAIURL, bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+		client := &http.Client{Timeout: generateTimeout(opts)}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+
+		var oaResp openAIResponse
+		if err := json.Unmarshal(body, &oaResp); err != nil {
+			return "", err
+		}
+
+		if oaResp.Error != nil {
+			return "", fmt.Errorf("openai API error: %s", oaResp.Error.Message)
+		}
+		if len(oaResp.Choices) == 0 {
+			return "", fmt.Errorf("no response from OpenAI API")
+		}
+
+		return strings.TrimSpace(oaResp.Choices[0].Message.Content), nil
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	return Completion{Text: text}, nil
+}
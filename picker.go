@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// pickerResult is what the user decided to do with a chosen candidate.
+type pickerResult struct {
+	message string
+	commit  bool
+}
+
+// runPicker shows an interactive, arrow-navigable list of candidate commit
+// messages and lets the user accept, edit, or regenerate them. regenerate is
+// invoked (and its result swapped in) whenever the user presses 'r'. If
+// stdin isn't a terminal, or raw mode can't be enabled, it falls back to the
+// first candidate so genie still works in pipes and scripts. When long is
+// true, pressing 'e' only edits the header line, preserving the body and
+// footers rather than discarding them.
+func runPicker(candidates []string, doCommit bool, long bool, regenerate func() ([]string, error)) (*pickerResult, error) {
+	if !isTerminal(os.Stdin) {
+		return &pickerResult{message: candidates[0], commit: doCommit}, nil
+	}
+
+	restore, err := enableRawMode()
+	if err != nil {
+		return &pickerResult{message: candidates[0], commit: doCommit}, nil
+	}
+	defer restore()
+
+	selected := 0
+	for {
+		renderCandidates(candidates, selected)
+		key, err := readKey()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case keyUp:
+			selected = (selected - 1 + len(candidates)) % len(candidates)
+		case keyDown:
+			selected = (selected + 1) % len(candidates)
+		case keyEnter, 'y', 'c':
+			return &pickerResult{message: candidates[selected], commit: doCommit}, nil
+		case 'e':
+			restore()
+			var edited string
+			if long {
+				edited, err = editHeader(candidates[selected])
+			} else {
+				edited, err = editLine(candidates[selected])
+			}
+			if err != nil {
+				return nil, err
+			}
+			candidates[selected] = edited
+			restore, err = enableRawMode()
+			if err != nil {
+				return &pickerResult{message: candidates[selected], commit: doCommit}, nil
+			}
+		case 'r':
+			restore()
+			grayf("🔄 Regenerating...\n")
+			fresh, err := regenerate()
+			if err != nil {
+				return nil, err
+			}
+			candidates = fresh
+			selected = 0
+			restore, err = enableRawMode()
+			if err != nil {
+				return &pickerResult{message: candidates[selected], commit: doCommit}, nil
+			}
+		case 'q', keyEsc, keyCtrlC:
+			return nil, fmt.Errorf("cancelled by user")
+		}
+	}
+}
+
+// renderCandidates redraws the picker: a header plus one line per candidate,
+// with the currently selected candidate highlighted.
+func renderCandidates(candidates []string, selected int) {
+	fmt.Print("\033[2J\033[H")
+	fmt.Print(colorBold + "✨ Pick a commit message" + colorReset + colorGray + "  (↑/↓ move, Enter/y accept, e edit, r regenerate, q quit)" + colorReset + "\r\n\r\n")
+	for i, c := range candidates {
+		summary, hasMore := firstLine(c)
+		if i == selected {
+			fmt.Print(colorCyan + "  ▸ " + summary + colorReset)
+		} else {
+			fmt.Print(colorGray + "    " + summary + colorReset)
+		}
+		if hasMore {
+			fmt.Print(colorGray + " (+body)" + colorReset)
+		}
+		fmt.Print("\r\n")
+	}
+	fmt.Print("\r\n")
+}
+
+// firstLine returns the header line of a (possibly multi-line, --long mode)
+// candidate, plus whether it has more content below it.
+func firstLine(candidate string) (string, bool) {
+	if idx := strings.IndexByte(candidate, '\n'); idx >= 0 {
+		return candidate[:idx], true
+	}
+	return candidate, false
+}
+
+// editLine lets the user replace a candidate by retyping it. genie has no
+// line-editing dependency, so this is a plain reprompt rather than true
+// inline cursor editing; leaving the line blank keeps the original.
+func editLine(current string) (string, error) {
+	fmt.Println()
+	grayf("Current: %s\n", current)
+	boldf("Edit (leave blank to keep): ")
+
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return current, nil
+	}
+	return line, nil
+}
+
+// editHeader lets the user retype just the header line of a --long mode
+// candidate, leaving its body and footers untouched. editLine's plain
+// reprompt can only capture a single line, so editing the whole candidate
+// that way would silently drop everything after the header.
+func editHeader(current string) (string, error) {
+	header, rest, hasRest := strings.Cut(current, "\n")
+	edited, err := editLine(header)
+	if err != nil {
+		return "", err
+	}
+	if !hasRest {
+		return edited, nil
+	}
+	return edited + "\n" + rest, nil
+}
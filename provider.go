@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// defaultGenerateTimeout matches the timeout the Gemini-only client used
+// before providers existed.
+const defaultGenerateTimeout = 30 * time.Second
+
+// Completion is a single text completion returned by a Provider.
+type Completion struct {
+	Text string
+}
+
+// GenerateOptions configures a single Provider.Generate call.
+type GenerateOptions struct {
+	Model   string
+	Timeout time.Duration
+}
+
+// Provider is the interface every LLM backend genie can talk to implements,
+// so the rest of the tool (prompt building, the picker, the validator)
+// stays identical no matter which model answers the prompt.
+type Provider interface {
+	// Name identifies the provider for error messages and logging.
+	Name() string
+	// Generate sends prompt to the backend and returns its raw text response.
+	Generate(ctx context.Context, prompt string, opts GenerateOptions) (Completion, error)
+}
+
+// NewProvider resolves name (from --provider or GENIE_PROVIDER) to a
+// concrete Provider, reading whatever API key or host env var it needs.
+func NewProvider(name string) (Provider, error) {
+	switch name {
+	case "", "gemini":
+		return newGeminiProvider()
+	case "openai":
+		return newOpenAIProvider()
+	case "anthropic":
+		return newAnthropicProvider()
+	case "ollama":
+		return newOllamaProvider()
+	default:
+		return nil, fmt.Errorf("unknown provider %q (supported: gemini, openai, anthropic, ollama)", name)
+	}
+}
+
+// resolveProviderName applies the --provider flag / GENIE_PROVIDER env var
+// precedence, defaulting to gemini so existing setups keep working.
+func resolveProviderName(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if env := os.Getenv("GENIE_PROVIDER"); env != "" {
+		return env
+	}
+	return "gemini"
+}
+
+// generateTimeout returns opts.Timeout if set, otherwise the shared default.
+func generateTimeout(opts GenerateOptions) time.Duration {
+	if opts.Timeout > 0 {
+		return opts.Timeout
+	}
+	return defaultGenerateTimeout
+}
+
+// requireEnv reads an environment variable, returning a setup error that
+// names both the variable and how to get it if it's unset.
+func requireEnv(key, hint string) (string, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return "", fmt.Errorf("%s environment variable not set\n   %s", key, hint)
+	}
+	return value, nil
+}
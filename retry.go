@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// retryAttempts and retryBaseDelay configure the shared backoff every
+// Provider.Generate implementation runs its HTTP call through, so a flaky
+// network blip doesn't immediately surface as a genie failure.
+const (
+	retryAttempts  = 3
+	retryBaseDelay = 500 * time.Millisecond
+)
+
+// withRetry calls fn up to retryAttempts times, backing off exponentially
+// between failures, and gives up early if ctx is cancelled.
+func withRetry(ctx context.Context, fn func() (string, error)) (string, error) {
+	var lastErr error
+	for attempt := 0; attempt < retryAttempts; attempt++ {
+		if attempt > 0 {
+			delay := retryBaseDelay * time.Duration(1<<uint(attempt-1))
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		text, err := fn()
+		if err == nil {
+			return text, nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
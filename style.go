@@ -0,0 +1,352 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// styleCacheFile is where StyleProfile is cached, inside .git so it never
+// ends up committed or clutters the working tree.
+const styleCacheFile = "genie-style.json"
+
+// styleSampleSize is how many recent commit subjects are sampled to detect
+// the repository's dominant type prefixes, emoji usage, and scope vocabulary.
+const styleSampleSize = 50
+
+// StyleProfile captures the conventions this repository already follows, so
+// generated messages can match them instead of falling back to the generic
+// guide baked into the prompt.
+type StyleProfile struct {
+	DominantTypes       []string `json:"dominant_types,omitempty"`
+	CommonScopes        []string `json:"common_scopes,omitempty"`
+	EmojiUsage          float64  `json:"emoji_usage"`
+	AllowedTypes        []string `json:"allowed_types,omitempty"`
+	ScopeEnum           []string `json:"scope_enum,omitempty"`
+	MaxSubjectLen       int      `json:"max_subject_len,omitempty"`
+	GitmessageTemplate  string   `json:"gitmessage_template,omitempty"`
+	ContributingExcerpt string   `json:"contributing_excerpt,omitempty"`
+}
+
+// LoadStyleProfile returns a StyleProfile for the current repository,
+// reusing .git/genie-style.json when it's newer than every file the profile
+// is derived from (the commit log, .gitmessage, CONTRIBUTING.md,
+// commitlint.config.js) and rebuilding it otherwise.
+func LoadStyleProfile() (StyleProfile, error) {
+	dir, err := gitDir()
+	if err != nil {
+		return StyleProfile{}, err
+	}
+	cachePath := filepath.Join(dir, styleCacheFile)
+
+	if cached, ok := readStyleCache(cachePath, dir); ok {
+		return cached, nil
+	}
+
+	profile, err := buildStyleProfile()
+	if err != nil {
+		return StyleProfile{}, err
+	}
+
+	writeStyleCache(cachePath, profile)
+	return profile, nil
+}
+
+func gitDir() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--git-dir").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// styleSignalFiles are the inputs that, if touched after the cache was
+// written, make the cached profile stale.
+func styleSignalFiles(dir string) []string {
+	return []string{
+		filepath.Join(dir, "logs", "HEAD"),
+		".gitmessage",
+		"CONTRIBUTING.md",
+		"commitlint.config.js",
+	}
+}
+
+func readStyleCache(cachePath, dir string) (StyleProfile, bool) {
+	cacheInfo, err := os.Stat(cachePath)
+	if err != nil {
+		return StyleProfile{}, false
+	}
+
+	for _, signal := range styleSignalFiles(dir) {
+		if info, err := os.Stat(signal); err == nil && info.ModTime().After(cacheInfo.ModTime()) {
+			return StyleProfile{}, false
+		}
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return StyleProfile{}, false
+	}
+
+	var profile StyleProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return StyleProfile{}, false
+	}
+	return profile, true
+}
+
+// writeStyleCache best-effort persists profile; a failure here (read-only
+// .git, no space left, ...) shouldn't block commit message generation.
+func writeStyleCache(path string, profile StyleProfile) {
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o644)
+}
+
+func buildStyleProfile() (StyleProfile, error) {
+	subjects, err := sampleCommitSubjects(styleSampleSize)
+	if err != nil {
+		return StyleProfile{}, err
+	}
+
+	profile := analyzeCommitSubjects(subjects)
+
+	if tmpl, ok := readGitmessageTemplate(); ok {
+		profile.GitmessageTemplate = tmpl
+	}
+	if excerpt, ok := readContributingExcerpt(); ok {
+		profile.ContributingExcerpt = excerpt
+	}
+	if rules, ok := readCommitlintConfig(); ok {
+		profile.AllowedTypes = rules.types
+		profile.ScopeEnum = rules.scopes
+		profile.MaxSubjectLen = rules.maxLen
+	}
+
+	return profile, nil
+}
+
+func sampleCommitSubjects(n int) ([]string, error) {
+	out, err := exec.Command("git", "log", fmt.Sprintf("-n%d", n), "--pretty=%s").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var subjects []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// analyzeCommitSubjects parses each subject with the same Parse used to
+// validate generated messages, and tallies type/emoji/scope usage across
+// whatever parses cleanly. Subjects that don't look like "type(scope): ..."
+// (merge commits, "WIP", ...) are simply skipped.
+func analyzeCommitSubjects(subjects []string) StyleProfile {
+	typeCounts := map[string]int{}
+	scopeCounts := map[string]int{}
+	emojiCount := 0
+	parsed := 0
+
+	for _, subject := range subjects {
+		pc, err := Parse(subject)
+		if err != nil {
+			continue
+		}
+		parsed++
+		typeCounts[pc.Type]++
+		if pc.Emoji != "" {
+			emojiCount++
+		}
+		if pc.Scope != "" {
+			scopeCounts[pc.Scope]++
+		}
+	}
+
+	profile := StyleProfile{
+		DominantTypes: topKeys(typeCounts, 5),
+		CommonScopes:  topKeys(scopeCounts, 5),
+	}
+	if parsed > 0 {
+		profile.EmojiUsage = float64(emojiCount) / float64(parsed)
+	}
+	return profile
+}
+
+// topKeys returns up to k keys of counts ordered by descending count, breaking
+// ties alphabetically so the result is deterministic.
+func topKeys(counts map[string]int, k int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > k {
+		keys = keys[:k]
+	}
+	return keys
+}
+
+// readGitmessageTemplate reads the repo's commit.template (git config wins
+// over a plain .gitmessage file at the repo root).
+func readGitmessageTemplate() (string, bool) {
+	path := gitConfigValue("commit.template")
+	if path == "" {
+		path = ".gitmessage"
+	}
+
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// readContributingExcerpt reads the first 200 lines of CONTRIBUTING.md, on
+// the assumption that any commit-message guidance it has lives near the top.
+func readContributingExcerpt() (string, bool) {
+	data, err := os.ReadFile("CONTRIBUTING.md")
+	if err != nil {
+		return "", false
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > 200 {
+		lines = lines[:200]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), true
+}
+
+// commitlintRules is the subset of a commitlint.config.js we know how to
+// pull out: the rules genie's own validator already understands.
+type commitlintRules struct {
+	types  []string
+	scopes []string
+	maxLen int
+}
+
+// These match commitlint's conventional-config shape, e.g.
+// 'type-enum': [2, 'always', ['feat', 'fix', ...]]. commitlint.config.js is a
+// real JS module, not JSON, so rather than embed a JS parser we pull out just
+// the handful of rules genie's validator cares about with regexes - good
+// enough for the common single-quoted array-literal style these configs use.
+var (
+	commitlintTypeEnumPattern  = regexp.MustCompile(`'type-enum'\s*:\s*\[\s*\d+\s*,\s*'[^']*'\s*,\s*\[([^\]]*)\]`)
+	commitlintScopeEnumPattern = regexp.MustCompile(`'scope-enum'\s*:\s*\[\s*\d+\s*,\s*'[^']*'\s*,\s*\[([^\]]*)\]`)
+	commitlintMaxLenPattern    = regexp.MustCompile(`'header-max-length'\s*:\s*\[\s*\d+\s*,\s*'[^']*'\s*,\s*(\d+)`)
+	commitlintListItemPattern  = regexp.MustCompile(`'([^']*)'`)
+)
+
+func readCommitlintConfig() (commitlintRules, bool) {
+	data, err := os.ReadFile("commitlint.config.js")
+	if err != nil {
+		return commitlintRules{}, false
+	}
+	text := string(data)
+
+	var rules commitlintRules
+	if m := commitlintTypeEnumPattern.FindStringSubmatch(text); m != nil {
+		rules.types = extractQuotedList(m[1])
+	}
+	if m := commitlintScopeEnumPattern.FindStringSubmatch(text); m != nil {
+		rules.scopes = extractQuotedList(m[1])
+	}
+	if m := commitlintMaxLenPattern.FindStringSubmatch(text); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			rules.maxLen = n
+		}
+	}
+
+	if len(rules.types) == 0 && len(rules.scopes) == 0 && rules.maxLen == 0 {
+		return commitlintRules{}, false
+	}
+	return rules, true
+}
+
+func extractQuotedList(s string) []string {
+	var out []string
+	for _, m := range commitlintListItemPattern.FindAllStringSubmatch(s, -1) {
+		out = append(out, m[1])
+	}
+	return out
+}
+
+// ApplyToRules merges whatever this profile learned from commitlint.config.js
+// into rules, leaving fields the profile has no opinion on untouched.
+func (p StyleProfile) ApplyToRules(rules ValidationRules) ValidationRules {
+	if len(p.AllowedTypes) > 0 {
+		rules.AllowedTypes = p.AllowedTypes
+	}
+	if len(p.ScopeEnum) > 0 {
+		quoted := make([]string, len(p.ScopeEnum))
+		for i, scope := range p.ScopeEnum {
+			quoted[i] = regexp.QuoteMeta(scope)
+		}
+		rules.ScopeRegex = "^(" + strings.Join(quoted, "|") + ")$"
+	}
+	if p.MaxSubjectLen > 0 {
+		rules.MaxSubjectLen = p.MaxSubjectLen
+	}
+	return rules
+}
+
+// houseStyleInstructions summarizes profile into a short prompt section so
+// generated messages match this repository's own conventions instead of the
+// generic guide above. Returns "" when the profile found nothing worth
+// mentioning (e.g. a brand new repo with no parseable commit history).
+func houseStyleInstructions(profile StyleProfile) string {
+	if len(profile.DominantTypes) == 0 && profile.GitmessageTemplate == "" && profile.ContributingExcerpt == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n🏠 HOUSE STYLE (learned from this repository's own commit history):\n")
+
+	if len(profile.DominantTypes) > 0 {
+		fmt.Fprintf(&b, "- Most commits use these types: %s\n", strings.Join(profile.DominantTypes, ", "))
+	}
+	if len(profile.CommonScopes) > 0 {
+		fmt.Fprintf(&b, "- Common scopes: %s\n", strings.Join(profile.CommonScopes, ", "))
+	}
+	if profile.EmojiUsage > 0 {
+		fmt.Fprintf(&b, "- About %.0f%% of recent commits lead with an emoji\n", profile.EmojiUsage*100)
+	} else {
+		b.WriteString("- Recent commits do not lead with an emoji\n")
+	}
+	if profile.GitmessageTemplate != "" {
+		fmt.Fprintf(&b, "- Repository .gitmessage template:\n%s\n", profile.GitmessageTemplate)
+	}
+	if profile.ContributingExcerpt != "" {
+		fmt.Fprintf(&b, "- CONTRIBUTING.md commit guidance (excerpt):\n%s\n", profile.ContributingExcerpt)
+	}
+	b.WriteString("Prefer this house style over the generic guide above when the two disagree.")
+
+	return b.String()
+}
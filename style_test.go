@@ -0,0 +1,37 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeCommitSubjects(t *testing.T) {
+	subjects := []string{
+		"✨ feat(parser): add emoji support",
+		"🐛 fix(parser): handle empty input",
+		"feat(cli): add --long flag",
+		"WIP",
+		"docs: update README",
+	}
+
+	profile := analyzeCommitSubjects(subjects)
+
+	if len(profile.DominantTypes) == 0 || profile.DominantTypes[0] != "feat" {
+		t.Errorf("DominantTypes = %v, want feat first (most frequent)", profile.DominantTypes)
+	}
+	if want := []string{"parser", "cli"}; !reflect.DeepEqual(profile.CommonScopes, want) {
+		t.Errorf("CommonScopes = %v, want %v", profile.CommonScopes, want)
+	}
+	// 2 of the 4 parseable subjects lead with an emoji; "WIP" fails to parse
+	// and is skipped entirely rather than counted as a non-emoji subject.
+	if want := 0.5; profile.EmojiUsage != want {
+		t.Errorf("EmojiUsage = %v, want %v", profile.EmojiUsage, want)
+	}
+}
+
+func TestAnalyzeCommitSubjectsNoneParseable(t *testing.T) {
+	profile := analyzeCommitSubjects([]string{"WIP", "merge branch 'main'"})
+	if len(profile.DominantTypes) != 0 || len(profile.CommonScopes) != 0 || profile.EmojiUsage != 0 {
+		t.Errorf("analyzeCommitSubjects() with no parseable subjects = %+v, want zero value", profile)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Key codes returned by readKey. Printable keys (e.g. 'e', 'r', 'q') are
+// returned as their own byte value instead of one of these constants.
+const (
+	keyNone = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyEsc
+	keyCtrlC
+)
+
+// isTerminal reports whether f is attached to an interactive terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// enableRawMode puts the controlling terminal into raw mode (no echo, no
+// line buffering) so the picker can read arrow keys one byte at a time. It
+// shells out to stty rather than touching termios directly, mirroring how
+// the rest of genie already delegates OS-specific work (clipboard, git) to
+// external commands. The returned func restores the previous settings and
+// is safe to call more than once.
+func enableRawMode() (func(), error) {
+	save := exec.Command("stty", "-g")
+	save.Stdin = os.Stdin
+	saved, err := save.Output()
+	if err != nil {
+		return nil, err
+	}
+	savedState := strings.TrimSpace(string(saved))
+
+	raw := exec.Command("stty", "raw", "-echo")
+	raw.Stdin = os.Stdin
+	if err := raw.Run(); err != nil {
+		return nil, err
+	}
+
+	restored := false
+	return func() {
+		if restored {
+			return
+		}
+		restored = true
+		cmd := exec.Command("stty", savedState)
+		cmd.Stdin = os.Stdin
+		cmd.Run()
+	}, nil
+}
+
+// readKey reads a single keypress from stdin, decoding arrow-key escape
+// sequences for up and down (ESC [ A/B) into keyUp/keyDown; any other
+// escape sequence comes back as keyNone.
+func readKey() (int, error) {
+	buf := make([]byte, 3)
+	n, err := os.Stdin.Read(buf[:1])
+	if err != nil || n == 0 {
+		return keyNone, err
+	}
+
+	switch buf[0] {
+	case '\r', '\n':
+		return keyEnter, nil
+	case 3:
+		return keyCtrlC, nil
+	case 27:
+		// Could be a bare Esc or the start of an arrow-key sequence; peek
+		// at the next two bytes to tell the difference.
+		n, _ := os.Stdin.Read(buf[1:3])
+		if n < 2 || buf[1] != '[' {
+			return keyEsc, nil
+		}
+		switch buf[2] {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		}
+		return keyNone, nil
+	default:
+		return int(buf[0]), nil
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// bodyWrapWidth is the column width bodies are wrapped to in --body/--long
+// mode, matching the 72-column convention git itself recommends.
+const bodyWrapWidth = 72
+
+// longModeInstructions is spliced into the commit-message prompt when
+// --body/--long is set, asking the provider to also produce a wrapped body and
+// machine-parseable footers. It's a no-op in the default single-line mode.
+func longModeInstructions(long bool) string {
+	if !long {
+		return ""
+	}
+	return `
+Also include a wrapped body (max ~72 columns per line) explaining WHAT changed
+and WHY, separated from the subject line by a single blank line. After the
+body, on their own lines following another blank line, add any relevant git
+trailers such as "Refs: #123", "BREAKING CHANGE: <description>", or
+"Co-authored-by: Name <email>" if the diff implies them. Leave trailers out
+entirely if none apply - don't invent them.`
+}
+
+// TrailerBuilder accumulates footers for a commit message, keeping the
+// first value seen for any given key so locally-derived trailers never
+// clobber an explicit one the provider already produced.
+type TrailerBuilder struct {
+	footers []Footer
+	seen    map[string]bool
+}
+
+// NewTrailerBuilder seeds a TrailerBuilder with footers already present in
+// a parsed commit (e.g. ones the provider generated itself).
+func NewTrailerBuilder(existing []Footer) *TrailerBuilder {
+	tb := &TrailerBuilder{seen: make(map[string]bool, len(existing))}
+	for _, f := range existing {
+		tb.add(f.Key, f.Value)
+	}
+	return tb
+}
+
+func (tb *TrailerBuilder) add(key, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	normalizedKey := strings.ToLower(key)
+	if tb.seen[normalizedKey] {
+		return
+	}
+	tb.seen[normalizedKey] = true
+	tb.footers = append(tb.footers, Footer{Key: key, Value: value})
+}
+
+// Footers returns the accumulated footers in insertion order.
+func (tb *TrailerBuilder) Footers() []Footer {
+	return tb.footers
+}
+
+// issueRefPattern pulls an issue reference like "ISSUE-123" out of a branch
+// name such as "feature/ISSUE-123-add-thing".
+var issueRefPattern = regexp.MustCompile(`[A-Za-z]+-\d+`)
+
+// PopulateLocalTrailers adds Refs/Source-Link/Signed-off-by trailers
+// derived from the local repository to tb, for anything the provider didn't
+// already supply for that key. Signed-off-by only fires when signoff is
+// true (--signoff) or user.signingkey is configured; user.email alone is
+// NOT a valid signal, since git requires it to make any commit at all.
+func PopulateLocalTrailers(tb *TrailerBuilder, signoff bool) {
+	if ref := branchIssueRef(); ref != "" {
+		tb.add("Refs", "#"+ref)
+	}
+
+	if link := remoteSourceLink(); link != "" {
+		tb.add("Source-Link", link)
+	}
+
+	if signoff || gitConfigValue("user.signingkey") != "" {
+		if email := gitConfigValue("user.email"); email != "" {
+			if name := gitConfigValue("user.name"); name != "" {
+				tb.add("Signed-off-by", fmt.Sprintf("%s <%s>", name, email))
+			} else {
+				tb.add("Signed-off-by", email)
+			}
+		}
+	}
+}
+
+// branchIssueRef extracts the numeric issue reference from the current
+// branch name, e.g. "feature/ISSUE-123-add-thing" -> "ISSUE-123".
+func branchIssueRef() string {
+	out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return issueRefPattern.FindString(strings.TrimSpace(string(out)))
+}
+
+func gitConfigValue(key string) string {
+	out, err := exec.Command("git", "config", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// remoteSourceLink turns the "origin" remote URL into an https:// link
+// suitable for a Source-Link trailer, handling both SSH and HTTPS remotes.
+func remoteSourceLink() string {
+	out, err := exec.Command("git", "config", "--get", "remote.origin.url").Output()
+	if err != nil {
+		return ""
+	}
+
+	url := strings.TrimSuffix(strings.TrimSpace(string(out)), ".git")
+	if strings.HasPrefix(url, "git@") {
+		url = strings.TrimPrefix(url, "git@")
+		url = strings.Replace(url, ":", "/", 1)
+		url = "https://" + url
+	}
+	return url
+}
+
+// applyTrailersToAll parses each candidate, applies ApplyTrailers, and
+// re-renders it; candidates that fail to parse are left untouched.
+func applyTrailersToAll(candidates []string, signoff bool) []string {
+	out := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		pc, err := Parse(candidate)
+		if err != nil {
+			out[i] = candidate
+			continue
+		}
+		ApplyTrailers(pc, signoff)
+		out[i] = pc.String()
+	}
+	return out
+}
+
+// ApplyTrailers wraps pc's body to bodyWrapWidth and merges its footers
+// with ones derived from the local repository, mutating pc in place.
+func ApplyTrailers(pc *ParsedCommit, signoff bool) {
+	if pc.Body != "" {
+		pc.Body = wrapBody(pc.Body, bodyWrapWidth)
+	}
+
+	tb := NewTrailerBuilder(pc.Footers)
+	PopulateLocalTrailers(tb, signoff)
+	pc.Footers = tb.Footers()
+}
+
+// wrapBody wraps body text to at most width characters per line, leaving
+// paragraph breaks (blank lines) intact.
+func wrapBody(body string, width int) string {
+	paragraphs := strings.Split(body, "\n\n")
+	for i, p := range paragraphs {
+		paragraphs[i] = wrapParagraph(p, width)
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+func wrapParagraph(p string, width int) string {
+	fields := strings.Fields(p)
+	if len(fields) == 0 {
+		return p
+	}
+
+	// Scripts with no word-separators (ja, zh, ...) come back from
+	// strings.Fields as a single "word" covering the whole paragraph;
+	// break anything wider than width into rune-sized chunks so --long
+	// bodies in --message-lang ja/zh still wrap instead of running on.
+	var words []string
+	for _, w := range fields {
+		words = append(words, splitLongWord(w, width)...)
+	}
+
+	var lines []string
+	line := words[0]
+	lineLen := utf8.RuneCountInString(line)
+	for _, w := range words[1:] {
+		wLen := utf8.RuneCountInString(w)
+		if lineLen+1+wLen > width {
+			lines = append(lines, line)
+			line = w
+			lineLen = wLen
+		} else {
+			line += " " + w
+			lineLen += 1 + wLen
+		}
+	}
+	lines = append(lines, line)
+
+	return strings.Join(lines, "\n")
+}
+
+// splitLongWord breaks w into width-rune chunks when it alone is wider than
+// width, measuring in runes (not bytes) so multi-byte scripts aren't cut
+// mid-character.
+func splitLongWord(w string, width int) []string {
+	runes := []rune(w)
+	if len(runes) <= width {
+		return []string{w}
+	}
+
+	var chunks []string
+	for len(runes) > width {
+		chunks = append(chunks, string(runes[:width]))
+		runes = runes[width:]
+	}
+	if len(runes) > 0 {
+		chunks = append(chunks, string(runes))
+	}
+	return chunks
+}
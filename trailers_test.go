@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestWrapBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		width int
+		want  string
+	}{
+		{
+			name:  "short line untouched",
+			body:  "a short body",
+			width: 72,
+			want:  "a short body",
+		},
+		{
+			name:  "wraps on whitespace",
+			body:  "one two three four five",
+			width: 10,
+			want:  "one two\nthree four\nfive",
+		},
+		{
+			name:  "preserves paragraph breaks",
+			body:  "first paragraph here\n\nsecond one",
+			width: 10,
+			want:  "first\nparagraph\nhere\n\nsecond one",
+		},
+		{
+			name:  "no-space script wraps by rune",
+			body:  "既存のコミットメッセージ生成ロジックを大幅に改善する",
+			width: 10,
+			want:  "既存のコミットメッセ\nージ生成ロジックを大\n幅に改善する",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapBody(tt.body, tt.width); got != tt.want {
+				t.Errorf("wrapBody(%q, %d) = %q, want %q", tt.body, tt.width, got, tt.want)
+			}
+		})
+	}
+}